@@ -0,0 +1,164 @@
+package main
+
+import (
+	"sync"
+
+	noise "github.com/KEINOS/go-noise"
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// BiomeConfig exposes the fractal Brownian motion parameters used to sample
+// the elevation, temperature and moisture fields that drive biome selection.
+type BiomeConfig struct {
+	Octaves         int
+	Lacunarity      float32
+	Gain            float32
+	ElevationSeed   int64
+	TemperatureSeed int64
+	MoistureSeed    int64
+	Scale           rl.Vector2
+}
+
+var biomeConfig = BiomeConfig{
+	Octaves:         4,
+	Lacunarity:      2.0,
+	Gain:            0.5,
+	ElevationSeed:   gameSettings.RNG_SEED,
+	TemperatureSeed: gameSettings.RNG_SEED + 1,
+	MoistureSeed:    gameSettings.RNG_SEED + 2,
+	Scale:           gameSettings.MAP_SCALAR,
+}
+
+// noiseSampler is the subset of the go-noise generator interface fbm needs,
+// used so cachedNoise can hand out a shared instance per seed instead of
+// reconstructing one on every call. Eval32 is variadic to match
+// noise.Generator.Eval32's signature exactly.
+type noiseSampler interface {
+	Eval32(dim ...float32) float32
+}
+
+var (
+	noiseCacheMu sync.RWMutex
+	noiseCache   = map[int64]noiseSampler{}
+)
+
+// cachedNoise returns the shared Perlin generator for seed, building it once
+// and reusing it thereafter. Safe for concurrent use by the chunk loader's
+// worker pool.
+func cachedNoise(seed int64) noiseSampler {
+	noiseCacheMu.RLock()
+	generator, ok := noiseCache[seed]
+	noiseCacheMu.RUnlock()
+	if ok {
+		return generator
+	}
+
+	noiseCacheMu.Lock()
+	defer noiseCacheMu.Unlock()
+	if generator, ok := noiseCache[seed]; ok {
+		return generator
+	}
+
+	generator, _ = noise.New(noise.Perlin, seed)
+	noiseCache[seed] = generator
+	return generator
+}
+
+// fbm samples a fractal Brownian motion field at the given world coordinate:
+// the sum of cfg.Octaves Perlin octaves, each one Lacunarity times higher
+// frequency and Gain times lower amplitude than the last, normalized back
+// into [0, 1].
+func fbm(coordinate rl.Vector2, seed int64, cfg BiomeConfig) float32 {
+	pNoise := cachedNoise(seed)
+
+	var sum float32
+	var amplitude float32 = 1
+	var frequency float32 = 1
+	var amplitudeSum float32 = 0
+
+	for octave := 0; octave < cfg.Octaves; octave++ {
+		sum += amplitude * pNoise.Eval32(
+			coordinate.X*frequency/cfg.Scale.X,
+			coordinate.Y*frequency/cfg.Scale.Y,
+		)
+		amplitudeSum += amplitude
+		amplitude *= cfg.Gain
+		frequency *= cfg.Lacunarity
+	}
+
+	return (sum/amplitudeSum + 1) / 2
+}
+
+// returns in range [0, 1]. expects x, y to be in tile coordinates
+func getHeight(coordinate rl.Vector2) float32 {
+	return fbm(coordinate, biomeConfig.ElevationSeed, biomeConfig)
+}
+
+// returns in range [0, 1], cold to hot
+func getTemperature(coordinate rl.Vector2) float32 {
+	return fbm(coordinate, biomeConfig.TemperatureSeed, biomeConfig)
+}
+
+// returns in range [0, 1], dry to wet
+func getMoisture(coordinate rl.Vector2) float32 {
+	return fbm(coordinate, biomeConfig.MoistureSeed, biomeConfig)
+}
+
+const (
+	beachElevation    = 0.45
+	highlandElevation = 0.72
+	peakElevation     = 0.85
+
+	coldTemperature = 0.35
+	hotTemperature  = 0.65
+
+	dryMoisture = 0.35
+	wetMoisture = 0.65
+)
+
+// lowlandBiomes and highlandBiomes are indexed [temperatureBucket][moistureBucket],
+// each bucket running cold/dry -> hot/wet.
+var lowlandBiomes = [3][3]TerrainType{
+	{Tundra, Tundra, Snow},
+	{Dirt, LowGrass, Swamp},
+	{Savanna, Jungle, Jungle},
+}
+
+var highlandBiomes = [3][3]TerrainType{
+	{Tundra, Snow, Snow},
+	{Gravel, HighGrass, Forest},
+	{Savanna, Forest, Forest},
+}
+
+func bucket(value, lowerBound, upperBound float32) int {
+	switch {
+	case value < lowerBound:
+		return 0
+	case value < upperBound:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// classifyBiome picks a TerrainType from elevation bands gated by a
+// temperature/moisture lookup, replacing the old single-Perlin height
+// classifier with a proper biome subsystem.
+func classifyBiome(elevation, temperature, moisture float32) TerrainType {
+	tempBucket := bucket(temperature, coldTemperature, hotTemperature)
+	moistBucket := bucket(moisture, dryMoisture, wetMoisture)
+
+	switch {
+	case elevation < beachElevation:
+		return Beach
+	case elevation < highlandElevation:
+		return lowlandBiomes[tempBucket][moistBucket]
+	case elevation < peakElevation:
+		return highlandBiomes[tempBucket][moistBucket]
+	default:
+		if temperature < coldTemperature {
+			return Snow
+		}
+		return Mountain
+	}
+}
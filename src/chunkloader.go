@@ -0,0 +1,248 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MakeChunkMap builds an empty ChunkMap backed by an LRU-bounded resident set
+// and a background ChunkLoader, so the render loop never blocks on Perlin
+// evaluation for a chunk it hasn't seen yet.
+func MakeChunkMap(seed int64) ChunkMap {
+	return ChunkMap{
+		coordToChunk:      make(map[Coordinate]*Chunk),
+		seed:              seed,
+		lruOrder:          list.New(),
+		lruElems:          make(map[Coordinate]*list.Element),
+		maxResidentChunks: gameSettings.MaxResidentChunks,
+		loader:            NewChunkLoader(seed, gameSettings.ChunkWorkerCount, gameSettings.ChunkQueueSize),
+		loadGeneration:    1,
+	}
+}
+
+// getChunk returns the resident chunk for chunkCoordinate, or an undefined
+// placeholder while the real chunk is generated on a background worker.
+func (cm *ChunkMap) getChunk(chunkCoordinate Coordinate) *Chunk {
+	if chunk, ok := cm.coordToChunk[chunkCoordinate]; ok {
+		cm.touch(chunkCoordinate)
+		return chunk
+	}
+
+	cm.loader.Enqueue(chunkCoordinate)
+
+	placeholder := &Chunk{tiles: MakeMatrix[Tile](int(gameSettings.CHUNK_SIZE.X), int(gameSettings.CHUNK_SIZE.Y))}
+	cm.insert(chunkCoordinate, placeholder)
+	return placeholder
+}
+
+// getChunkSync returns the resident, fully-loaded chunk for chunkCoordinate,
+// forcing a synchronous load/generate if it isn't resident yet instead of
+// just enqueuing it for the background workers. Callers like FindPath need a
+// real answer in the same call, not "try again next frame".
+func (cm *ChunkMap) getChunkSync(chunkCoordinate Coordinate) *Chunk {
+	if chunk, ok := cm.coordToChunk[chunkCoordinate]; ok && chunk.ready {
+		cm.touch(chunkCoordinate)
+		return chunk
+	}
+
+	chunk := buildChunk(cm.seed, chunkCoordinate)
+	cm.insert(chunkCoordinate, chunk)
+	return chunk
+}
+
+// rawTileAt reads a tile straight out of its chunk's tile matrix, enqueuing
+// that chunk via getChunk if it isn't resident yet but never triggering
+// normals/hydrology on it. Sampling passes that read neighboring chunks (e.g.
+// hillshading, flow accumulation) must go through here instead of getTile, so
+// that computing one chunk's cached data doesn't cascade into computing its
+// neighbors' too. ready reports whether the chunk is real generated/loaded
+// data as opposed to an unfilled placeholder.
+func (cm *ChunkMap) rawTileAt(worldTileCoord Coordinate) (tile Tile, ready bool) {
+	tileVec := coordinateToVec2(worldTileCoord)
+	chunkVec := tileVec
+	toChunkCoords(&chunkVec)
+
+	coordInChunkX := mod(int32(tileVec.X), int32(gameSettings.CHUNK_SIZE.X))
+	coordInChunkY := mod(int32(tileVec.Y), int32(gameSettings.CHUNK_SIZE.Y))
+
+	chunk := cm.getChunk(rlVector2ToCoordinate(&chunkVec))
+	return chunk.tiles.At(int(coordInChunkX), int(coordInChunkY)), chunk.ready
+}
+
+// DrainLoader swaps in every chunk the background workers have finished
+// building since the last call. Call once per frame from the main loop.
+func (cm *ChunkMap) DrainLoader() {
+	for {
+		select {
+		case result := <-cm.loader.results:
+			cm.insert(result.coord, result.chunk)
+			cm.loader.markDone(result.coord)
+		default:
+			return
+		}
+	}
+}
+
+// PreloadAround enqueues every chunk within radius of center that isn't
+// already resident, so the ring around the camera is generated ahead of time.
+// Resident placeholders that are still !ready are re-enqueued too: Enqueue's
+// request channel is best-effort and can be full, in which case the original
+// getChunk call that created the placeholder silently dropped its only
+// enqueue attempt, leaving it stuck forever. Enqueue's pending guard makes
+// this safe to call every pass - it's a no-op for anything already queued or
+// being built.
+func (cm *ChunkMap) PreloadAround(center Coordinate, radius int) {
+	for dy := int32(-radius); dy <= int32(radius); dy++ {
+		for dx := int32(-radius); dx <= int32(radius); dx++ {
+			coord := Coordinate{X: center.X + dx, Y: center.Y + dy}
+			if chunk, ok := cm.coordToChunk[coord]; ok {
+				cm.touch(coord)
+				if !chunk.ready {
+					cm.loader.Enqueue(coord)
+				}
+				continue
+			}
+			cm.loader.Enqueue(coord)
+		}
+	}
+}
+
+// insert makes chunk the resident chunk for coord. If it's replacing an
+// unready placeholder that was painted on in the meantime (see SetTile),
+// those edits are replayed onto the incoming chunk instead of being
+// silently discarded.
+func (cm *ChunkMap) insert(coord Coordinate, chunk *Chunk) {
+	if existing, ok := cm.coordToChunk[coord]; ok && existing != chunk && len(existing.editedTiles) > 0 {
+		for idx, t := range existing.editedTiles {
+			chunk.tiles.data[idx] = t
+		}
+		chunk.dirty = true
+		chunk.editedTiles = existing.editedTiles
+	}
+
+	if elem, ok := cm.lruElems[coord]; ok {
+		cm.lruOrder.Remove(elem)
+	}
+	cm.coordToChunk[coord] = chunk
+	cm.lruElems[coord] = cm.lruOrder.PushFront(coord)
+	if chunk.ready {
+		cm.loadGeneration++
+	}
+	cm.evictIfNeeded()
+}
+
+func (cm *ChunkMap) touch(coord Coordinate) {
+	if elem, ok := cm.lruElems[coord]; ok {
+		cm.lruOrder.MoveToFront(elem)
+	}
+}
+
+// evictIfNeeded drops the least-recently-used chunks once the resident set
+// grows past maxResidentChunks, flushing dirty ones to disk first so painted
+// tiles survive the eviction.
+func (cm *ChunkMap) evictIfNeeded() {
+	for cm.maxResidentChunks > 0 && len(cm.coordToChunk) > cm.maxResidentChunks {
+		oldest := cm.oldestEvictable()
+		if oldest == nil {
+			return
+		}
+
+		coord := oldest.Value.(Coordinate)
+		if chunk := cm.coordToChunk[coord]; chunk != nil && chunk.dirty {
+			_ = writeChunk(chunkFilePath(cm.seed, coord), chunk)
+		}
+
+		cm.lruOrder.Remove(oldest)
+		delete(cm.lruElems, coord)
+		delete(cm.coordToChunk, coord)
+	}
+}
+
+// oldestEvictable walks the LRU list from the back looking for a chunk that's
+// safe to evict, skipping over not-yet-ready placeholders. Evicting one of
+// those would drop it from coordToChunk entirely, so any edit recorded on it
+// (see Chunk.editedTiles) would vanish with no real chunk left for insert to
+// replay it onto once the background loader's result arrives - and if the
+// placeholder was dirty, evictIfNeeded would persist that near-blank chunk to
+// disk, permanently overwriting whatever was actually there.
+func (cm *ChunkMap) oldestEvictable() *list.Element {
+	for elem := cm.lruOrder.Back(); elem != nil; elem = elem.Prev() {
+		coord := elem.Value.(Coordinate)
+		if chunk := cm.coordToChunk[coord]; chunk != nil && !chunk.ready {
+			continue
+		}
+		return elem
+	}
+	return nil
+}
+
+type chunkJobResult struct {
+	coord Coordinate
+	chunk *Chunk
+}
+
+// ChunkLoader is a worker pool that builds chunks (from a save file if one
+// exists, otherwise from noise) off the main goroutine. Workers never touch
+// ChunkMap directly; they only produce results, which the main loop swaps in
+// via DrainLoader.
+type ChunkLoader struct {
+	seed     int64
+	requests chan Coordinate
+	results  chan chunkJobResult
+
+	mu      sync.Mutex
+	pending map[Coordinate]bool
+}
+
+func NewChunkLoader(seed int64, workerCount, queueSize int) *ChunkLoader {
+	loader := &ChunkLoader{
+		seed:     seed,
+		requests: make(chan Coordinate, queueSize),
+		results:  make(chan chunkJobResult, queueSize),
+		pending:  make(map[Coordinate]bool),
+	}
+
+	for i := 0; i < workerCount; i++ {
+		go loader.worker()
+	}
+	return loader
+}
+
+func (l *ChunkLoader) worker() {
+	for coord := range l.requests {
+		l.results <- chunkJobResult{coord: coord, chunk: buildChunk(l.seed, coord)}
+	}
+}
+
+// Enqueue schedules coord for background generation, skipping it if it's
+// already queued or being built.
+func (l *ChunkLoader) Enqueue(coord Coordinate) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.pending[coord] {
+		return
+	}
+
+	select {
+	case l.requests <- coord:
+		l.pending[coord] = true
+	default:
+		// request queue is full; the next pre-generation tick will retry
+	}
+}
+
+func (l *ChunkLoader) markDone(coord Coordinate) {
+	l.mu.Lock()
+	delete(l.pending, coord)
+	l.mu.Unlock()
+}
+
+// buildChunk loads coord's saved chunk off disk if one exists, otherwise
+// generates it from noise. Safe to call from any worker goroutine.
+func buildChunk(seed int64, coord Coordinate) *Chunk {
+	if loaded, err := loadChunk(seed, coord); err == nil {
+		return loaded
+	}
+	return generateChunk(coordinateToVec2(coord))
+}
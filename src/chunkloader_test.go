@@ -0,0 +1,91 @@
+package main
+
+import (
+	"container/list"
+	"testing"
+)
+
+// testChunkMap builds a bare ChunkMap with the LRU bookkeeping initialized
+// but no background loader, so insert/touch/evictIfNeeded can be exercised
+// directly without spinning up worker goroutines or touching noise/disk.
+func testChunkMap(maxResidentChunks int) *ChunkMap {
+	return &ChunkMap{
+		coordToChunk:      make(map[Coordinate]*Chunk),
+		lruOrder:          list.New(),
+		lruElems:          make(map[Coordinate]*list.Element),
+		maxResidentChunks: maxResidentChunks,
+	}
+}
+
+func readyChunk() *Chunk {
+	return &Chunk{tiles: MakeMatrix[Tile](1, 1), ready: true}
+}
+
+func TestEvictIfNeededDropsLeastRecentlyUsed(t *testing.T) {
+	cm := testChunkMap(2)
+
+	cm.insert(Coordinate{0, 0}, readyChunk())
+	cm.insert(Coordinate{1, 0}, readyChunk())
+	cm.insert(Coordinate{2, 0}, readyChunk())
+
+	if _, ok := cm.coordToChunk[Coordinate{0, 0}]; ok {
+		t.Error("evictIfNeeded should have dropped the least recently touched chunk {0 0}")
+	}
+	for _, coord := range []Coordinate{{1, 0}, {2, 0}} {
+		if _, ok := cm.coordToChunk[coord]; !ok {
+			t.Errorf("evictIfNeeded should not have dropped %v", coord)
+		}
+	}
+}
+
+func TestTouchProtectsChunkFromEviction(t *testing.T) {
+	cm := testChunkMap(2)
+
+	cm.insert(Coordinate{0, 0}, readyChunk())
+	cm.insert(Coordinate{1, 0}, readyChunk())
+	cm.touch(Coordinate{0, 0}) // {0 0} is now the most recently used, {1 0} the least
+	cm.insert(Coordinate{2, 0}, readyChunk())
+
+	if _, ok := cm.coordToChunk[Coordinate{0, 0}]; !ok {
+		t.Error("touch should have protected {0 0} from eviction")
+	}
+	if _, ok := cm.coordToChunk[Coordinate{1, 0}]; ok {
+		t.Error("{1 0} should have been evicted as the least recently used chunk")
+	}
+}
+
+func TestOldestEvictableSkipsUnreadyPlaceholders(t *testing.T) {
+	cm := testChunkMap(0) // maxResidentChunks 0 means evictIfNeeded never fires on its own
+
+	placeholder := &Chunk{tiles: MakeMatrix[Tile](1, 1)} // ready: false
+	cm.insert(Coordinate{0, 0}, placeholder)
+	cm.insert(Coordinate{1, 0}, readyChunk())
+
+	elem := cm.oldestEvictable()
+	if elem == nil {
+		t.Fatal("oldestEvictable should have found the ready chunk at {1 0}")
+	}
+	if got := elem.Value.(Coordinate); got != (Coordinate{1, 0}) {
+		t.Errorf("oldestEvictable returned %v, want {1 0} - an unready placeholder must never be evicted", got)
+	}
+}
+
+func TestInsertReplaysEditsOntoIncomingChunk(t *testing.T) {
+	cm := testChunkMap(0)
+
+	placeholder := &Chunk{
+		tiles:       MakeMatrix[Tile](2, 1),
+		editedTiles: map[int]Tile{0: {Terrain: Dirt}},
+	}
+	cm.coordToChunk[Coordinate{0, 0}] = placeholder
+
+	real := &Chunk{tiles: MakeMatrix[Tile](2, 1), ready: true}
+	cm.insert(Coordinate{0, 0}, real)
+
+	if got := real.tiles.At(0, 0); got.Terrain != Dirt {
+		t.Errorf("insert should have replayed the edited tile onto the real chunk, got terrain %v", got.Terrain)
+	}
+	if !real.dirty {
+		t.Error("insert should mark the real chunk dirty so the replayed edit gets saved")
+	}
+}
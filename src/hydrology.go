@@ -0,0 +1,113 @@
+package main
+
+import "sort"
+
+// ensureHydrology runs the flow-accumulation pass for a chunk the first time
+// it's needed and caches the result, so repeated getTile calls don't re-carve
+// rivers every frame.
+//
+// If the pass bails out for lack of a ready neighbor, it's throttled to at
+// most once per cm.loadGeneration instead of retrying every call - without
+// that, every getTile near the load frontier would re-sample the padded
+// 75x75-cell height window (up to its first unready tile) once per on-screen
+// tile, every frame, until the neighbor loads.
+func (cm *ChunkMap) ensureHydrology(chunkCoord Coordinate, chunk *Chunk) {
+	if chunk.riversReady || chunk.riversStaleGen == cm.loadGeneration {
+		return
+	}
+	if !cm.computeChunkHydrology(chunkCoord, chunk) {
+		chunk.riversStaleGen = cm.loadGeneration
+	}
+}
+
+// computeChunkHydrology accumulates downhill flow over a padded 3x3-chunk
+// window centered on chunkCoord: every tile above WATER_LEVEL hands its flow
+// to its steepest-descent neighbor, and tiles whose accumulated flow clears
+// RiverFlowThreshold are marked waterlogged and carved by CarveDepth. Working
+// over the padded window keeps accumulation correct across chunk borders.
+//
+// If any chunk in the window is still an unloaded placeholder, the pass bails
+// out without caching anything (reporting false) so a stale height-0 reading
+// from a chunk the background loader hasn't filled in yet never gets baked
+// permanently into the center chunk's rivers - ensureHydrology will retry
+// once the loader makes progress.
+func (cm *ChunkMap) computeChunkHydrology(chunkCoord Coordinate, chunk *Chunk) bool {
+	chunkW, chunkH := chunk.tiles.w, chunk.tiles.h
+	windowW, windowH := chunkW*3, chunkH*3
+	originX := chunkCoord.X*int32(chunkW) - int32(chunkW)
+	originY := chunkCoord.Y*int32(chunkH) - int32(chunkH)
+
+	heights := make([]float32, windowW*windowH)
+	for y := 0; y < windowH; y++ {
+		for x := 0; x < windowW; x++ {
+			worldCoord := Coordinate{X: originX + int32(x), Y: originY + int32(y)}
+			tile, ready := cm.rawTileAt(worldCoord)
+			if !ready {
+				return false
+			}
+			heights[y*windowW+x] = tile.Height
+		}
+	}
+
+	type cell struct{ x, y int }
+	order := make([]cell, 0, windowW*windowH)
+	for y := 0; y < windowH; y++ {
+		for x := 0; x < windowW; x++ {
+			order = append(order, cell{x, y})
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return heights[order[i].y*windowW+order[i].x] > heights[order[j].y*windowW+order[j].x]
+	})
+
+	flow := make([]float32, windowW*windowH)
+	for i := range flow {
+		flow[i] = 1
+	}
+
+	for _, c := range order {
+		height := heights[c.y*windowW+c.x]
+		if height < gameSettings.WATER_LEVEL {
+			continue
+		}
+
+		steepestX, steepestY, steepestHeight := -1, -1, height
+		for _, offset := range neighborOffsets {
+			nx, ny := c.x+int(offset.X), c.y+int(offset.Y)
+			if nx < 0 || ny < 0 || nx >= windowW || ny >= windowH {
+				continue
+			}
+			if nh := heights[ny*windowW+nx]; nh < steepestHeight {
+				steepestX, steepestY, steepestHeight = nx, ny, nh
+			}
+		}
+
+		if steepestX >= 0 {
+			flow[steepestY*windowW+steepestX] += flow[c.y*windowW+c.x]
+		}
+	}
+
+	rivers := MakeMatrix[bool](chunkW, chunkH)
+	for y := 0; y < chunkH; y++ {
+		for x := 0; x < chunkW; x++ {
+			windowX, windowY := x+chunkW, y+chunkH
+			if flow[windowY*windowW+windowX] < gameSettings.RiverFlowThreshold {
+				continue
+			}
+
+			rivers.Set(x, y, true)
+
+			tile := chunk.tiles.At(x, y)
+			tile.Waterlogged = true
+			tile.Height -= gameSettings.CarveDepth
+			if tile.Height < 0 {
+				tile.Height = 0
+			}
+			chunk.tiles.Set(x, y, tile)
+		}
+	}
+
+	chunk.rivers = rivers
+	chunk.riversReady = true
+	return true
+}
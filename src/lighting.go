@@ -0,0 +1,107 @@
+package main
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// Lighting controls the hillshading pass applied to every drawn tile.
+type Lighting struct {
+	Direction rl.Vector3
+	Ambient   float32
+	Strength  float32
+}
+
+// getNormal returns the cached surface normal for a tile, computing and
+// caching the whole owning chunk's normals on first access.
+func (cm *ChunkMap) getNormal(tileCoord rl.Vector2) rl.Vector3 {
+	chunkVec := tileCoord
+	toChunkCoords(&chunkVec)
+	chunkCoord := rlVector2ToCoordinate(&chunkVec)
+
+	coordInChunkX := mod(int32(tileCoord.X), int32(gameSettings.CHUNK_SIZE.X))
+	coordInChunkY := mod(int32(tileCoord.Y), int32(gameSettings.CHUNK_SIZE.Y))
+
+	chunk := cm.getChunk(chunkCoord)
+	// If a previous attempt bailed for lack of a ready neighbor, don't retry
+	// the full normals pass again until cm.loadGeneration has actually moved
+	// (i.e. some chunk finished loading) - otherwise every on-screen tile near
+	// the load frontier re-samples its four neighbors every single frame.
+	if !chunk.normalsReady && chunk.normalsStaleGen != cm.loadGeneration {
+		if !cm.computeChunkNormals(chunkCoord, chunk) {
+			chunk.normalsStaleGen = cm.loadGeneration
+		}
+	}
+	// chunk.normals is only allocated once computeChunkNormals finishes; a
+	// chunk that's still a placeholder (or still waiting on a neighbor) has
+	// no cache to index yet, so hand back a flat up-facing normal instead.
+	if !chunk.normalsReady {
+		return rl.NewVector3(0, 0, 1)
+	}
+	return chunk.normals.At(int(coordInChunkX), int(coordInChunkY))
+}
+
+// computeChunkNormals samples the four orthogonal neighbors of every tile in
+// the chunk (paging neighboring chunks in via rawTileAt where needed) and
+// caches the resulting normals so draw() doesn't recompute them every frame.
+//
+// If a neighbor is still an unloaded placeholder, the pass bails out without
+// caching anything (reporting false), so a stale height-0 reading never gets
+// baked permanently into this chunk's normals - getNormal will retry once the
+// loader makes progress.
+func (cm *ChunkMap) computeChunkNormals(chunkCoord Coordinate, chunk *Chunk) bool {
+	w, h := chunk.tiles.w, chunk.tiles.h
+	normals := MakeMatrix[rl.Vector3](w, h)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			worldX := chunkCoord.X*int32(w) + int32(x)
+			worldY := chunkCoord.Y*int32(h) + int32(y)
+
+			left, leftReady := cm.rawTileAt(Coordinate{X: worldX - 1, Y: worldY})
+			right, rightReady := cm.rawTileAt(Coordinate{X: worldX + 1, Y: worldY})
+			top, topReady := cm.rawTileAt(Coordinate{X: worldX, Y: worldY - 1})
+			bottom, bottomReady := cm.rawTileAt(Coordinate{X: worldX, Y: worldY + 1})
+			if !leftReady || !rightReady || !topReady || !bottomReady {
+				return false
+			}
+
+			normals.Set(x, y, surfaceNormal(left.Height, right.Height, top.Height, bottom.Height))
+		}
+	}
+
+	chunk.normals = normals
+	chunk.normalsReady = true
+	return true
+}
+
+// surfaceNormal builds a unit normal from the finite-difference height
+// gradient between a tile's left/right and top/bottom neighbors.
+func surfaceNormal(hL, hR, hT, hB float32) rl.Vector3 {
+	gradX := hR - hL
+	gradY := hB - hT
+	return rl.Vector3Normalize(rl.NewVector3(-gradX, -gradY, 1))
+}
+
+// shadeColor modulates a tile's base color by the dot product of its normal
+// and the configured light direction, clamped to an ambient floor.
+func shadeColor(base rl.Color, normal rl.Vector3) rl.Color {
+	lighting := gameSettings.Lighting
+	lightDir := rl.Vector3Normalize(lighting.Direction)
+
+	diffuse := rl.Vector3DotProduct(normal, lightDir)
+	if diffuse < 0 {
+		diffuse = 0
+	}
+
+	factor := lighting.Ambient + (1-lighting.Ambient)*diffuse*lighting.Strength
+	if factor > 1 {
+		factor = 1
+	}
+
+	return rl.NewColor(
+		uint8(float32(base.R)*factor),
+		uint8(float32(base.G)*factor),
+		uint8(float32(base.B)*factor),
+		base.A,
+	)
+}
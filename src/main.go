@@ -1,10 +1,10 @@
 package main
 
 import (
+	"container/list"
 	"fmt"
 	"math"
 
-	noise "github.com/KEINOS/go-noise"
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
@@ -19,12 +19,20 @@ const (
 	HighGrass        TerrainType = iota
 	Forest           TerrainType = iota
 	Mountain         TerrainType = iota
+	Snow             TerrainType = iota
+	Tundra           TerrainType = iota
+	Savanna          TerrainType = iota
+	Swamp            TerrainType = iota
+	Jungle           TerrainType = iota
+	Beach            TerrainType = iota
 )
 
+// Tile's fields are exported (despite being accessed only within main) so
+// that gob can encode them when persistence.go saves a chunk to disk.
 type Tile struct {
-	terrain     TerrainType
-	waterlogged bool
-	height      float32
+	Terrain     TerrainType
+	Waterlogged bool
+	Height      float32
 }
 
 // maps from terrain type to color
@@ -37,6 +45,12 @@ var terrianTypeToColor = map[TerrainType]rl.Color{
 	HighGrass:        rl.Green,
 	Forest:           rl.DarkGreen,
 	Mountain:         rl.White,
+	Snow:             rl.RayWhite,
+	Tundra:           rl.LightGray,
+	Savanna:          rl.Orange,
+	Swamp:            rl.DarkGray,
+	Jungle:           rl.NewColor(13, 117, 63, 255),
+	Beach:            rl.NewColor(237, 201, 175, 255),
 }
 
 type Integer interface {
@@ -49,13 +63,13 @@ func mod[T Integer](a, b T) T {
 
 // tries to return color, but if it fails returns magenta and error
 func getColorForTerrain(tile *Tile) (rl.Color, error) {
-	if tile.waterlogged {
+	if tile.Waterlogged {
 		return rl.Blue, nil
 	}
 
-	color, ok := terrianTypeToColor[tile.terrain]
+	color, ok := terrianTypeToColor[tile.Terrain]
 	if !ok {
-		return rl.Magenta, fmt.Errorf("no color for terrain type %v", tile.terrain)
+		return rl.Magenta, fmt.Errorf("no color for terrain type %v", tile.Terrain)
 	}
 	return color, nil
 }
@@ -67,6 +81,15 @@ var gameSettings = struct {
 	MAP_SCALAR  rl.Vector2
 	CHUNK_SIZE  rl.Vector2
 	WATER_LEVEL float32
+	Lighting    Lighting
+
+	RiverFlowThreshold float32
+	CarveDepth         float32
+
+	MaxResidentChunks int
+	ChunkWorkerCount  int
+	ChunkQueueSize    int
+	PreloadRadius     int
 }{
 	rl.NewVector2(5, 5),
 	rl.NewColor(255, 255, 255, 255),
@@ -74,10 +97,24 @@ var gameSettings = struct {
 	rl.NewVector2(50, 50),
 	rl.NewVector2(25, 25),
 	0.4,
+	Lighting{
+		Direction: rl.NewVector3(-0.5, -0.5, 1),
+		Ambient:   0.35,
+		Strength:  1.0,
+	},
+
+	40,
+	0.03,
+
+	256,
+	4,
+	512,
+	2,
 }
 
-func (tile *Tile) drawTile(screenPos rl.Vector2) {
+func (tile *Tile) drawTile(screenPos rl.Vector2, normal rl.Vector3) {
 	color, _ := getColorForTerrain(tile)
+	color = shadeColor(color, normal)
 	rl.DrawRectangle(
 		int32(screenPos.X),
 		int32(screenPos.Y),
@@ -101,11 +138,39 @@ func floor32Int(x float32) int32 {
 // TODO implement chunking and infinite rendering
 
 type Chunk struct {
-	tiles Matrix[Tile]
+	tiles        Matrix[Tile]
+	dirty        bool
+	normals      Matrix[rl.Vector3]
+	normalsReady bool
+	// normalsStaleGen mirrors riversStaleGen for computeChunkNormals.
+	normalsStaleGen int
+	rivers          Matrix[bool]
+	riversReady     bool
+	// riversStaleGen is the ChunkMap.loadGeneration at which
+	// computeChunkHydrology last bailed out for lack of a ready neighbor, so
+	// ensureHydrology doesn't retry the pass again until a new chunk has
+	// actually loaded.
+	riversStaleGen int
+	// ready is false for the all-UndefinedTerrain placeholder getChunk hands
+	// back while the real chunk is still being built on a background worker.
+	ready bool
+	// editedTiles records local tile indices (y*w+x) painted by SetTile while
+	// this chunk was still an unready placeholder, so insert can replay them
+	// onto the real chunk once it arrives instead of silently discarding them.
+	editedTiles map[int]Tile
 }
 
 type ChunkMap struct {
-	coordToChunk map[Coordinate]*Chunk
+	coordToChunk      map[Coordinate]*Chunk
+	seed              int64
+	lruOrder          *list.List
+	lruElems          map[Coordinate]*list.Element
+	maxResidentChunks int
+	loader            *ChunkLoader
+	// loadGeneration counts real (ready) chunks inserted so far. Starts at 1
+	// so a fresh Chunk's zero-value generation-tracking fields never match
+	// it by coincidence.
+	loadGeneration int
 }
 
 func generateChunk(chunkCoordinate rl.Vector2) *Chunk {
@@ -116,25 +181,44 @@ func generateChunk(chunkCoordinate rl.Vector2) *Chunk {
 			worldCoord := chunkCoordinate
 			scaleVec2(&worldCoord, gameSettings.CHUNK_SIZE.X, gameSettings.CHUNK_SIZE.Y)
 			translateVec2(&worldCoord, x, y)
-			height := getHeight(worldCoord)
+
+			elevation := getHeight(worldCoord)
+			temperature := getTemperature(worldCoord)
+			moisture := getMoisture(worldCoord)
 
 			tiles.Set(int(x), int(y), Tile{
-				terrain:     getTerrain(height),
-				waterlogged: height < gameSettings.WATER_LEVEL,
-				height:      height,
+				Terrain:     classifyBiome(elevation, temperature, moisture),
+				Waterlogged: elevation < gameSettings.WATER_LEVEL,
+				Height:      elevation,
 			})
 		}
 	}
-	return &Chunk{tiles}
+	return &Chunk{tiles: tiles, ready: true}
 }
 
-func (c *ChunkMap) getChunk(chunkCoordinate Coordinate) *Chunk {
-	chunk, ok := c.coordToChunk[chunkCoordinate]
-	if !ok {
-		chunk = generateChunk(chunkCoordinate)
-		c.coordToChunk[chunkCoordinate] = chunk
+// SetTile paints a single world tile and marks its owning chunk dirty so it
+// gets persisted on the next Save instead of being regenerated from noise.
+func (cm *ChunkMap) SetTile(world Coordinate, t Tile) {
+	tileVec := coordinateToVec2(world)
+	chunkVec := tileVec
+	toChunkCoords(&chunkVec)
+
+	coordInChunkX := mod(int32(tileVec.X), int32(gameSettings.CHUNK_SIZE.X))
+	coordInChunkY := mod(int32(tileVec.Y), int32(gameSettings.CHUNK_SIZE.Y))
+
+	chunk := cm.getChunk(rlVector2ToCoordinate(&chunkVec))
+	chunk.tiles.Set(int(coordInChunkX), int(coordInChunkY), t)
+	chunk.dirty = true
+
+	if !chunk.ready {
+		// the real chunk hasn't arrived yet - remember this edit so insert
+		// can replay it onto the real chunk instead of losing it when the
+		// background loader's result overwrites this placeholder.
+		if chunk.editedTiles == nil {
+			chunk.editedTiles = make(map[int]Tile)
+		}
+		chunk.editedTiles[int(coordInChunkY)*chunk.tiles.w+int(coordInChunkX)] = t
 	}
-	return chunk
 }
 
 func (cm *ChunkMap) getTile(tileCoord rl.Vector2) Tile {
@@ -146,10 +230,33 @@ func (cm *ChunkMap) getTile(tileCoord rl.Vector2) Tile {
 
 	// // Make chunk edges magenta
 	// if coordInChunk.X == 0 || coordInChunk.Y == 0 {
-	// 	return Tile{terrain: UndefinedTerrain}
+	// 	return Tile{Terrain: UndefinedTerrain}
 	// }
 
-	chunk := cm.getChunk(rlVector2ToCoordinate(chunkCoord))
+	chunkCoordinate := rlVector2ToCoordinate(chunkCoord)
+	chunk := cm.getChunk(chunkCoordinate)
+	cm.ensureHydrology(chunkCoordinate, chunk)
+	return chunk.tiles.At(
+		int(coordInChunkX),
+		int(coordInChunkY),
+	)
+}
+
+// getTileSync is getTile but forces a synchronous load/generate of the owning
+// chunk instead of handing back an UndefinedTerrain placeholder. FindPath
+// needs this: an unloaded chunk along the search area isn't impassable
+// terrain, it's just not known yet, and the synchronous A* call has no
+// "come back next frame" to fall back on the way draw() does.
+func (cm *ChunkMap) getTileSync(tileCoord rl.Vector2) Tile {
+	chunkCoord := &tileCoord
+	toChunkCoords(chunkCoord)
+
+	coordInChunkX := mod(int32(tileCoord.X), int32(gameSettings.CHUNK_SIZE.X))
+	coordInChunkY := mod(int32(tileCoord.Y), int32(gameSettings.CHUNK_SIZE.Y))
+
+	chunkCoordinate := rlVector2ToCoordinate(chunkCoord)
+	chunk := cm.getChunkSync(chunkCoordinate)
+	cm.ensureHydrology(chunkCoordinate, chunk)
 	return chunk.tiles.At(
 		int(coordInChunkX),
 		int(coordInChunkY),
@@ -165,41 +272,6 @@ func MakeMatrix[T any](w, h int) Matrix[T] { return Matrix[T]{w, h, make([]T, w*
 func (m Matrix[T]) At(x, y int) T          { return m.data[y*m.w+x] }
 func (m Matrix[T]) Set(x, y int, t T)      { m.data[y*m.w+x] = t }
 
-// returns in range [0, 1]
-// expects x, y to be in tile coordinates
-func getHeight(coordinate *rl.Vector2) float32 {
-	pNoise, _ := noise.New(noise.Perlin, gameSettings.RNG_SEED)
-	var height float32 = pNoise.Eval32(
-		coordinate.X/gameSettings.MAP_SCALAR.X,
-		coordinate.Y/gameSettings.MAP_SCALAR.Y,
-	)
-	return (height + 1) / 2
-}
-
-// Keep this in order of increasing height
-var heightBoundaryToTile = []struct {
-	UpperBound float32
-	Terrain    TerrainType
-}{
-	{0.42, Sand},
-	{0.45, Gravel},
-	{0.47, Dirt},
-	{0.55, LowGrass},
-	{0.6, HighGrass},
-	{0.78, Forest},
-	{1.0, Mountain},
-}
-
-func getTerrain(height float32) TerrainType {
-	for _, tileAtBound := range heightBoundaryToTile {
-		if height < tileAtBound.UpperBound {
-			return tileAtBound.Terrain
-		}
-	}
-
-	return UndefinedTerrain
-}
-
 type Camera struct {
 	center rl.Vector2
 }
@@ -209,9 +281,11 @@ func toChunkCoords(tileCoord *rl.Vector2) {
 }
 
 type Game struct {
-	windowSize rl.Vector2
-	camera     Camera
-	chunkMap   ChunkMap
+	windowSize      rl.Vector2
+	camera          Camera
+	chunkMap        ChunkMap
+	lastPath        []Coordinate
+	selectedTerrain TerrainType
 }
 
 func toTileCoord(worldCoord *rl.Vector2) {
@@ -259,7 +333,9 @@ func (g *Game) draw() {
 
 			g.toWorldCoord(&v)
 			toTileCoord(&v)
-			g.chunkMap.getTile(v).drawTile(v)
+			tile := g.chunkMap.getTile(v)
+			normal := g.chunkMap.getNormal(v)
+			tile.drawTile(v, normal)
 			// tile := g.chunkMap.getTile(NewVector2Int32FromRl(&v))
 
 			// v_world := rl.NewVector2(
@@ -274,6 +350,9 @@ func (g *Game) draw() {
 		}
 	}
 
+	if g.lastPath != nil {
+		g.drawPath(g.lastPath)
+	}
 }
 
 func (g *Game) handleInput() {
@@ -291,13 +370,94 @@ func (g *Game) handleInput() {
 		g.camera.center.X += moveSpeed
 	}
 
+	// demo: press P to path from the camera to a fixed offset, showing the
+	// pathfinder paging in chunks and dodging impassable terrain along the way
+	if rl.IsKeyPressed(rl.KeyP) {
+		cameraTile := g.camera.center
+		toTileCoord(&cameraTile)
+		start := rlVector2ToCoordinate(&cameraTile)
+		goal := Coordinate{X: start.X + 40, Y: start.Y + 40}
+
+		path, err := g.chunkMap.FindPath(start, goal)
+		if err != nil {
+			fmt.Printf("pathfinding demo: %v\n", err)
+			return
+		}
+		g.lastPath = path
+	}
+
+	// 1/2 cycle the brush's selected TerrainType forward/backward
+	if rl.IsKeyPressed(rl.KeyOne) {
+		g.cycleSelectedTerrain(1)
+	}
+	if rl.IsKeyPressed(rl.KeyTwo) {
+		g.cycleSelectedTerrain(-1)
+	}
+
+	g.handleBrushInput()
+
+	if rl.IsKeyPressed(rl.KeyK) {
+		if err := g.chunkMap.Save(); err != nil {
+			fmt.Printf("save failed: %v\n", err)
+		}
+	}
+}
+
+// paintableTerrains is the set of TerrainTypes the brush can cycle through.
+// UndefinedTerrain is left out since it's a "no data yet" sentinel, not a
+// real terrain to paint.
+var paintableTerrains = []TerrainType{
+	Sand, Gravel, Dirt, LowGrass, HighGrass, Forest, Mountain,
+	Snow, Tundra, Savanna, Swamp, Jungle, Beach,
+}
+
+// cycleSelectedTerrain moves the brush's selected TerrainType forward or
+// backward through paintableTerrains by step, wrapping around either end.
+func (g *Game) cycleSelectedTerrain(step int) {
+	index := 0
+	for i, terrain := range paintableTerrains {
+		if terrain == g.selectedTerrain {
+			index = i
+			break
+		}
+	}
+
+	index = mod(index+step, len(paintableTerrains))
+	g.selectedTerrain = paintableTerrains[index]
+}
+
+// handleBrushInput lets the player paint the map at runtime: left-click stamps
+// the currently selected TerrainType, right-click toggles waterlogged.
+func (g *Game) handleBrushInput() {
+	if !rl.IsMouseButtonDown(rl.MouseLeftButton) && !rl.IsMouseButtonDown(rl.MouseRightButton) {
+		return
+	}
+
+	mouseWorld := rl.GetMousePosition()
+	g.toWorldCoord(&mouseWorld)
+	toTileCoord(&mouseWorld)
+	tileCoord := rlVector2ToCoordinate(&mouseWorld)
+
+	if rl.IsMouseButtonDown(rl.MouseLeftButton) {
+		existing := g.chunkMap.getTile(coordinateToVec2(tileCoord))
+		g.chunkMap.SetTile(tileCoord, Tile{
+			Terrain:     g.selectedTerrain,
+			Waterlogged: existing.Waterlogged,
+			Height:      existing.Height,
+		})
+		return
+	}
+
+	existing := g.chunkMap.getTile(coordinateToVec2(tileCoord))
+	existing.Waterlogged = !existing.Waterlogged
+	g.chunkMap.SetTile(tileCoord, existing)
 }
 
 func main() {
 	game := Game{
-		Vector2Int32{1080, 720},
-		Camera{rl.NewVector2(0, 0)},
-		ChunkMap{make(map[Vector2Int32]*Chunk)},
+		windowSize: Vector2Int32{1080, 720},
+		camera:     Camera{rl.NewVector2(0, 0)},
+		chunkMap:   MakeChunkMap(gameSettings.RNG_SEED),
 	}
 
 	rl.InitWindow(int32(game.windowSize.X),
@@ -311,6 +471,13 @@ func main() {
 	for !rl.WindowShouldClose() {
 		rl.BeginDrawing()
 		rl.ClearBackground(gameSettings.CLEAR_COLOR)
+
+		game.chunkMap.DrainLoader()
+		cameraChunk := game.camera.center
+		toTileCoord(&cameraChunk)
+		toChunkCoords(&cameraChunk)
+		game.chunkMap.PreloadAround(rlVector2ToCoordinate(&cameraChunk), gameSettings.PreloadRadius)
+
 		game.draw()
 		game.handleInput()
 		rl.EndDrawing()
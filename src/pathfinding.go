@@ -0,0 +1,215 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// per-terrain movement cost, higher is slower. Mountain is impassable.
+var terrainMovementCost = map[TerrainType]float32{
+	Sand:      1.0,
+	Gravel:    1.2,
+	Dirt:      1.0,
+	LowGrass:  1.1,
+	HighGrass: 1.4,
+	Forest:    2.5,
+	Mountain:  float32(math.Inf(1)),
+	Snow:      2.0,
+	Tundra:    1.3,
+	Savanna:   1.1,
+	Swamp:     2.8,
+	Jungle:    2.6,
+	Beach:     1.0,
+}
+
+// defaultMovementCost is used for any TerrainType missing from
+// terrainMovementCost, so introducing a new biome can't silently wall off
+// the terrain it covers the way a missing-entry-as-impassable default would.
+const defaultMovementCost float32 = 1.5
+
+var pathfindingSettings = struct {
+	// hard cap on the number of tiles the search may expand before giving up
+	NodeBudget int
+	// allow diagonal moves that clip between two blocking orthogonal tiles
+	AllowCornerCutting bool
+	// allow traversing waterlogged tiles (at a cost penalty) instead of treating them as walls
+	AllowSwimming bool
+	SwimCost      float32
+}{
+	NodeBudget:         20000,
+	AllowCornerCutting: false,
+	AllowSwimming:      false,
+	SwimCost:           3.0,
+}
+
+func coordinateToVec2(c Coordinate) rl.Vector2 {
+	return rl.NewVector2(float32(c.X), float32(c.Y))
+}
+
+// cost of entering tile, or false if the tile cannot be entered at all
+func tileMoveCost(tile Tile) (float32, bool) {
+	if tile.Terrain == UndefinedTerrain {
+		// no real data yet (e.g. an unloaded placeholder chunk) - don't route through it
+		return 0, false
+	}
+
+	if tile.Waterlogged {
+		if !pathfindingSettings.AllowSwimming {
+			return 0, false
+		}
+		return pathfindingSettings.SwimCost, true
+	}
+
+	cost, ok := terrainMovementCost[tile.Terrain]
+	if !ok {
+		cost = defaultMovementCost
+	}
+	if math.IsInf(float64(cost), 1) {
+		return 0, false
+	}
+	return cost, true
+}
+
+// octile distance, admissible heuristic for 8-connected grids with unit orthogonal cost
+func octileDistance(a, b Coordinate) float32 {
+	dx := math.Abs(float64(a.X - b.X))
+	dy := math.Abs(float64(a.Y - b.Y))
+	const sqrt2MinusOne = math.Sqrt2 - 1
+	return float32(math.Max(dx, dy) + sqrt2MinusOne*math.Min(dx, dy))
+}
+
+var neighborOffsets = []Coordinate{
+	{X: 1, Y: 0}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: -1},
+	{X: 1, Y: 1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: -1, Y: -1},
+}
+
+type pathNode struct {
+	coord    Coordinate
+	gCost    float32
+	fCost    float32
+	index    int // heap index, maintained by container/heap
+}
+
+type pathNodeHeap []*pathNode
+
+func (h pathNodeHeap) Len() int            { return len(h) }
+func (h pathNodeHeap) Less(i, j int) bool  { return h[i].fCost < h[j].fCost }
+func (h pathNodeHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *pathNodeHeap) Push(x any) {
+	n := x.(*pathNode)
+	n.index = len(*h)
+	*h = append(*h, n)
+}
+func (h *pathNodeHeap) Pop() any {
+	old := *h
+	n := len(old)
+	node := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return node
+}
+
+// FindPath runs A* over the lazily generated chunk map from start to goal, paging in
+// chunks on demand via ChunkMap.getTileSync, which forces a synchronous load/generate
+// instead of handing back an unloaded placeholder the way the renderer's getTile does.
+// It gives up once pathfindingSettings.NodeBudget tiles have been expanded, returning an
+// error rather than searching forever.
+func (cm *ChunkMap) FindPath(start, goal Coordinate) ([]Coordinate, error) {
+	open := &pathNodeHeap{}
+	heap.Init(open)
+	heap.Push(open, &pathNode{coord: start, gCost: 0, fCost: octileDistance(start, goal)})
+
+	cameFrom := map[Coordinate]Coordinate{}
+	bestG := map[Coordinate]float32{start: 0}
+	expanded := 0
+
+	for open.Len() > 0 {
+		if expanded >= pathfindingSettings.NodeBudget {
+			return nil, fmt.Errorf("pathfinding: exceeded node budget of %d tiles before reaching goal", pathfindingSettings.NodeBudget)
+		}
+		current := heap.Pop(open).(*pathNode)
+		expanded++
+
+		if current.coord == goal {
+			return reconstructPath(cameFrom, current.coord), nil
+		}
+
+		for _, offset := range neighborOffsets {
+			neighbor := Coordinate{X: current.coord.X + offset.X, Y: current.coord.Y + offset.Y}
+
+			if offset.X != 0 && offset.Y != 0 && !pathfindingSettings.AllowCornerCutting {
+				if !canEnter(cm, Coordinate{X: current.coord.X + offset.X, Y: current.coord.Y}) ||
+					!canEnter(cm, Coordinate{X: current.coord.X, Y: current.coord.Y + offset.Y}) {
+					continue
+				}
+			}
+
+			tile := cm.getTileSync(coordinateToVec2(neighbor))
+			moveCost, ok := tileMoveCost(tile)
+			if !ok {
+				continue
+			}
+			if offset.X != 0 && offset.Y != 0 {
+				moveCost *= float32(math.Sqrt2)
+			}
+
+			tentativeG := current.gCost + moveCost
+			if existingG, ok := bestG[neighbor]; ok && tentativeG >= existingG {
+				continue
+			}
+
+			cameFrom[neighbor] = current.coord
+			bestG[neighbor] = tentativeG
+			heap.Push(open, &pathNode{
+				coord: neighbor,
+				gCost: tentativeG,
+				fCost: tentativeG + octileDistance(neighbor, goal),
+			})
+		}
+	}
+
+	return nil, fmt.Errorf("pathfinding: no path found from %v to %v", start, goal)
+}
+
+func canEnter(cm *ChunkMap, coord Coordinate) bool {
+	_, ok := tileMoveCost(cm.getTileSync(coordinateToVec2(coord)))
+	return ok
+}
+
+func reconstructPath(cameFrom map[Coordinate]Coordinate, end Coordinate) []Coordinate {
+	path := []Coordinate{end}
+	for {
+		prev, ok := cameFrom[path[len(path)-1]]
+		if !ok {
+			break
+		}
+		path = append(path, prev)
+	}
+
+	// reverse into start->goal order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// drawPath overlays the last computed path as small markers on top of the regular tile grid.
+func (g *Game) drawPath(path []Coordinate) {
+	for _, tileCoord := range path {
+		v := coordinateToVec2(tileCoord)
+		scaleVec2(&v, gameSettings.TILE_SIZE.X, gameSettings.TILE_SIZE.Y)
+		g.toScreenCoord(&v)
+		rl.DrawCircle(
+			int32(v.X)+int32(gameSettings.TILE_SIZE.X)/2,
+			int32(v.Y)+int32(gameSettings.TILE_SIZE.Y)/2,
+			gameSettings.TILE_SIZE.X/4,
+			rl.Red,
+		)
+	}
+}
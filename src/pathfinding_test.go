@@ -0,0 +1,144 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestOctileDistance(t *testing.T) {
+	cases := []struct {
+		a, b Coordinate
+		want float32
+	}{
+		{Coordinate{0, 0}, Coordinate{0, 0}, 0},
+		{Coordinate{0, 0}, Coordinate{5, 0}, 5},
+		{Coordinate{0, 0}, Coordinate{0, 5}, 5},
+		// diagonal moves cost sqrt2 each, so a straight diagonal is cheaper
+		// than the corresponding orthogonal Manhattan distance.
+		{Coordinate{0, 0}, Coordinate{3, 3}, 3 * sqrt2},
+	}
+
+	for _, c := range cases {
+		if got := octileDistance(c.a, c.b); !floatsClose(got, c.want, 1e-4) {
+			t.Errorf("octileDistance(%v, %v) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func floatsClose(a, b, epsilon float32) bool {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= epsilon
+}
+
+const sqrt2 = 1.4142135
+
+func TestTileMoveCost(t *testing.T) {
+	if _, ok := tileMoveCost(Tile{Terrain: UndefinedTerrain}); ok {
+		t.Error("tileMoveCost should refuse to route through an UndefinedTerrain placeholder tile")
+	}
+
+	if _, ok := tileMoveCost(Tile{Terrain: Mountain}); ok {
+		t.Error("tileMoveCost should treat Mountain as impassable")
+	}
+
+	if _, ok := tileMoveCost(Tile{Terrain: Dirt, Waterlogged: true}); ok {
+		t.Error("tileMoveCost should refuse waterlogged tiles when AllowSwimming is false")
+	}
+
+	cost, ok := tileMoveCost(Tile{Terrain: Dirt})
+	if !ok || cost != terrainMovementCost[Dirt] {
+		t.Errorf("tileMoveCost(Dirt) = (%v, %v), want (%v, true)", cost, ok, terrainMovementCost[Dirt])
+	}
+}
+
+func TestReconstructPath(t *testing.T) {
+	cameFrom := map[Coordinate]Coordinate{
+		{1, 0}: {0, 0},
+		{2, 0}: {1, 0},
+	}
+
+	path := reconstructPath(cameFrom, Coordinate{2, 0})
+	want := []Coordinate{{0, 0}, {1, 0}, {2, 0}}
+	if len(path) != len(want) {
+		t.Fatalf("reconstructPath returned %v, want %v", path, want)
+	}
+	for i := range want {
+		if path[i] != want[i] {
+			t.Errorf("reconstructPath()[%d] = %v, want %v", i, path[i], want[i])
+		}
+	}
+}
+
+// singleChunkMap builds a ChunkMap with one ready chunk at {0,0} filled with
+// terrain, walled in by its eight neighbor chunks, which are ready but left
+// all-UndefinedTerrain so tileMoveCost refuses to enter them. FindPath then
+// only ever looks up tiles of those nine resident chunks, so it runs entirely
+// against getChunkSync's already-resident fast path without touching the
+// loader or noise generation.
+func singleChunkMap(terrain func(x, y int) TerrainType) *ChunkMap {
+	w, h := int(gameSettings.CHUNK_SIZE.X), int(gameSettings.CHUNK_SIZE.Y)
+	tiles := MakeMatrix[Tile](w, h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			tiles.Set(x, y, Tile{Terrain: terrain(x, y)})
+		}
+	}
+
+	cm := &ChunkMap{
+		coordToChunk: map[Coordinate]*Chunk{
+			{0, 0}: {tiles: tiles, ready: true},
+		},
+		lruOrder: nil,
+		lruElems: nil,
+	}
+	for dy := int32(-1); dy <= 1; dy++ {
+		for dx := int32(-1); dx <= 1; dx++ {
+			if dx != 0 || dy != 0 {
+				cm.coordToChunk[Coordinate{dx, dy}] = &Chunk{tiles: MakeMatrix[Tile](w, h), ready: true}
+			}
+		}
+	}
+	return cm
+}
+
+func TestFindPathStraightLine(t *testing.T) {
+	cm := singleChunkMap(func(x, y int) TerrainType { return Dirt })
+
+	path, err := cm.FindPath(Coordinate{0, 0}, Coordinate{4, 0})
+	if err != nil {
+		t.Fatalf("FindPath returned error: %v", err)
+	}
+	if path[0] != (Coordinate{0, 0}) || path[len(path)-1] != (Coordinate{4, 0}) {
+		t.Errorf("FindPath path = %v, want it to run from {0 0} to {4 0}", path)
+	}
+}
+
+func TestFindPathBlockedByMountainWall(t *testing.T) {
+	// a vertical wall of Mountain at x == 2 with no gap, splitting the chunk
+	// in two - there is no valid path across it.
+	cm := singleChunkMap(func(x, y int) TerrainType {
+		if x == 2 {
+			return Mountain
+		}
+		return Dirt
+	})
+
+	if _, err := cm.FindPath(Coordinate{0, 0}, Coordinate{4, 0}); err == nil {
+		t.Error("FindPath should fail to cross a solid Mountain wall, got a path")
+	}
+}
+
+func TestFindPathRespectsNodeBudget(t *testing.T) {
+	originalBudget := pathfindingSettings.NodeBudget
+	pathfindingSettings.NodeBudget = 2
+	defer func() { pathfindingSettings.NodeBudget = originalBudget }()
+
+	cm := singleChunkMap(func(x, y int) TerrainType { return Dirt })
+
+	_, err := cm.FindPath(Coordinate{0, 0}, Coordinate{20, 20})
+	if err == nil {
+		t.Error("FindPath should give up once NodeBudget is exceeded instead of searching forever")
+	}
+}
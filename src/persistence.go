@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func saveDir(seed int64) string {
+	return filepath.Join("saves", fmt.Sprintf("%d", seed))
+}
+
+func chunkFilePath(seed int64, coord Coordinate) string {
+	return filepath.Join(saveDir(seed), fmt.Sprintf("%d_%d.chunk", coord.X, coord.Y))
+}
+
+// Save writes every dirty, fully-loaded chunk to saves/<seed>/ as gob-encoded
+// tiles and clears their dirty flags. Untouched chunks are left to regenerate
+// from noise on the next run, so only the player's edits ever hit disk.
+func (cm *ChunkMap) Save() error {
+	if err := os.MkdirAll(saveDir(cm.seed), 0o755); err != nil {
+		return fmt.Errorf("persistence: could not create save dir: %w", err)
+	}
+
+	for coord, chunk := range cm.coordToChunk {
+		if !chunk.dirty {
+			continue
+		}
+		if !chunk.ready {
+			// still an unready placeholder - its tiles are mostly
+			// UndefinedTerrain filler, not real generated terrain. Leave it
+			// dirty so the next Save picks it up once DrainLoader has
+			// replaced it with the real chunk (which carries the edit
+			// forward via insert).
+			continue
+		}
+
+		if err := writeChunk(chunkFilePath(cm.seed, coord), chunk); err != nil {
+			return fmt.Errorf("persistence: could not save chunk %v: %w", coord, err)
+		}
+		chunk.dirty = false
+	}
+	return nil
+}
+
+func writeChunk(path string, chunk *Chunk) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return gob.NewEncoder(file).Encode(chunk.tiles.data)
+}
+
+// loadChunk reads a previously saved chunk back off disk, returning an error
+// if it was never painted on (and therefore never saved).
+func loadChunk(seed int64, coord Coordinate) (*Chunk, error) {
+	file, err := os.Open(chunkFilePath(seed, coord))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	tiles := MakeMatrix[Tile](int(gameSettings.CHUNK_SIZE.X), int(gameSettings.CHUNK_SIZE.Y))
+	if err := gob.NewDecoder(file).Decode(&tiles.data); err != nil {
+		return nil, fmt.Errorf("persistence: could not decode chunk %v: %w", coord, err)
+	}
+	return &Chunk{tiles: tiles, ready: true}, nil
+}